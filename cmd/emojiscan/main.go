@@ -0,0 +1,103 @@
+// emojiscan, highlight recognized emoji in stdin using the emojitable package
+// Copyright (C) 2024  Steven Garf
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/stgarf/alfredmoji/emojitable"
+	"github.com/stgarf/alfredmoji/internal/emojisource"
+)
+
+// unicodeVersion is a flag to specify the Unicode version to load emoji from.
+var unicodeVersion = flag.String("version", "15.1", "Unicode version to use; only 15.1 is pinned for offline use (default: 15.1)")
+
+// unicodeEmojiURL is the URL to download the emoji data from.
+var unicodeEmojiURL = "https://unicode.org/Public/emoji/%s/emoji-test.txt"
+
+// loadTable fetches emoji-test.txt for version, preferring the pinned
+// offline data in internal/emojidata the same way the snippet-pack
+// generator does, and indexes every fully-qualified emoji into an
+// EmojiTable.
+func loadTable(version string) (*emojitable.EmojiTable, error) {
+	lines, err := emojisource.FetchVersionedEmojiData(unicodeEmojiURL, version)
+	if err != nil {
+		return nil, err
+	}
+
+	table := emojitable.New()
+
+	for _, line := range lines {
+		if !strings.Contains(line, "; fully-qualified") || !strings.Contains(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "#", 2)
+		emoji, description := emojisource.ExtractDescriptionAndEmoji(parts[1])
+		if emoji == "" {
+			continue
+		}
+
+		table.Add(emojitable.EmojiData{
+			Emoji:       emoji,
+			Description: description,
+			Aliases:     []string{description},
+		})
+	}
+
+	return table, nil
+}
+
+// highlight wraps every emoji sequence recognized by table in [[ ]] markers.
+func highlight(table *emojitable.EmojiTable, line string) string {
+	var out strings.Builder
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		if emoji := table.Find(string(runes[i:])); emoji != nil {
+			out.WriteString("[[")
+			out.WriteString(emoji.Emoji)
+			out.WriteString("]]")
+			i += len([]rune(emoji.Emoji))
+			continue
+		}
+		out.WriteRune(runes[i])
+		i++
+	}
+
+	return out.String()
+}
+
+func main() {
+	flag.Parse()
+
+	table, err := loadTable(*unicodeVersion)
+	if err != nil {
+		fmt.Printf("Error loading emoji data: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Loaded %d emoji sequences (Unicode %s)\n", table.Length(), *unicodeVersion)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fmt.Println(highlight(table, scanner.Text()))
+	}
+}
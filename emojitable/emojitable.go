@@ -0,0 +1,95 @@
+// Package emojitable indexes emoji sequences by rune prefix, so callers can
+// look up the longest recognized emoji sequence starting at a given point
+// in a string. It gives downstream Go consumers a runtime matcher API
+// instead of only the alfredmoji snippet-pack generator.
+package emojitable
+
+// EmojiData is the parsed emoji metadata carried by an EmojiTable entry.
+type EmojiData struct {
+	Emoji       string
+	Description string
+	Aliases     []string
+}
+
+// node is one rune of a trie path. A node with IsEmoji true terminates a
+// recognized emoji sequence, with Codepoint holding the runes walked to
+// reach it and Data holding the associated EmojiData.
+type node struct {
+	Codepoint []rune
+	IsEmoji   bool
+	Data      *EmojiData
+	Sub       map[rune]*node
+}
+
+// EmojiTable indexes a set of emoji by the rune sequence that spells them,
+// so the longest match at the start of a string can be resolved greedily.
+// This lets multi-rune ZWJ and skin-tone sequences resolve correctly
+// instead of stopping at their first rune.
+type EmojiTable struct {
+	root   *node
+	length int
+}
+
+// New returns an empty EmojiTable.
+func New() *EmojiTable {
+	return &EmojiTable{root: &node{Sub: make(map[rune]*node)}}
+}
+
+// Add indexes one emoji's rune sequence under its EmojiData.
+func (t *EmojiTable) Add(emoji EmojiData) {
+	runes := []rune(emoji.Emoji)
+	if len(runes) == 0 {
+		return
+	}
+
+	current := t.root
+	for i, r := range runes {
+		next, ok := current.Sub[r]
+		if !ok {
+			next = &node{Codepoint: runes[:i+1], Sub: make(map[rune]*node)}
+			current.Sub[r] = next
+		}
+		current = next
+	}
+
+	if !current.IsEmoji {
+		t.length++
+	}
+	data := emoji
+	current.IsEmoji = true
+	current.Data = &data
+}
+
+// Find returns the longest emoji sequence starting at the beginning of s,
+// or nil if s does not start with a recognized emoji.
+func (t *EmojiTable) Find(s string) *EmojiData {
+	current := t.root
+	var longest *node
+
+	for _, r := range s {
+		next, ok := current.Sub[r]
+		if !ok {
+			break
+		}
+		current = next
+		if current.IsEmoji {
+			longest = current
+		}
+	}
+
+	if longest == nil {
+		return nil
+	}
+	return longest.Data
+}
+
+// IsEmoji reports whether r begins at least one indexed emoji sequence.
+func (t *EmojiTable) IsEmoji(r rune) bool {
+	_, ok := t.root.Sub[r]
+	return ok
+}
+
+// Length returns the number of emoji sequences indexed in the table.
+func (t *EmojiTable) Length() int {
+	return t.length
+}
@@ -0,0 +1,79 @@
+package emojitable
+
+import "testing"
+
+func TestFindLongestMatch(t *testing.T) {
+	table := New()
+	table.Add(EmojiData{Emoji: "👍", Description: "thumbs up"})
+	table.Add(EmojiData{Emoji: "👍🏽", Description: "thumbs up: medium skin tone"})
+	table.Add(EmojiData{Emoji: "👨‍👩‍👧‍👦", Description: "family: man, woman, girl, boy"})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain emoji", "👍 nice", "thumbs up"},
+		{"prefers longer skin-tone sequence over its prefix", "👍🏽", "thumbs up: medium skin tone"},
+		{"multi-rune ZWJ sequence", "👨‍👩‍👧‍👦!", "family: man, woman, girl, boy"},
+		{"no match", "hello", ""},
+		{"empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := table.Find(tt.in)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("Find(%q) = %+v, want nil", tt.in, got)
+				}
+				return
+			}
+			if got == nil || got.Description != tt.want {
+				t.Fatalf("Find(%q) = %+v, want Description %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEmojiAndLength(t *testing.T) {
+	table := New()
+	if table.Length() != 0 {
+		t.Fatalf("Length() on empty table = %d, want 0", table.Length())
+	}
+
+	table.Add(EmojiData{Emoji: "👍", Description: "thumbs up"})
+	table.Add(EmojiData{Emoji: "👍🏽", Description: "thumbs up: medium skin tone"})
+
+	if table.Length() != 2 {
+		t.Fatalf("Length() = %d, want 2", table.Length())
+	}
+	if !table.IsEmoji('👍') {
+		t.Fatalf("IsEmoji('👍') = false, want true")
+	}
+	if table.IsEmoji('x') {
+		t.Fatalf("IsEmoji('x') = true, want false")
+	}
+}
+
+func TestAddIgnoresEmptyEmoji(t *testing.T) {
+	table := New()
+	table.Add(EmojiData{Emoji: "", Description: "nothing"})
+
+	if table.Length() != 0 {
+		t.Fatalf("Length() = %d, want 0 after adding an empty emoji", table.Length())
+	}
+}
+
+func TestAddOverwritesSameSequence(t *testing.T) {
+	table := New()
+	table.Add(EmojiData{Emoji: "👍", Description: "first"})
+	table.Add(EmojiData{Emoji: "👍", Description: "second"})
+
+	if table.Length() != 1 {
+		t.Fatalf("Length() = %d, want 1 after re-adding the same sequence", table.Length())
+	}
+	if got := table.Find("👍"); got == nil || got.Description != "second" {
+		t.Fatalf("Find(%q) = %+v, want Description %q", "👍", got, "second")
+	}
+}
@@ -0,0 +1,26 @@
+// Package emojidata embeds a pinned copy of the Unicode emoji source files
+// alfredmoji parses (emoji-test.txt, emoji-sequences.txt,
+// emoji-zwj-sequences.txt), so the CLI can build a snippet pack fully
+// offline and reproducibly for that one version instead of always hitting
+// unicode.org at runtime.
+//
+// Only 15.1 is pinned today: each version directory under versions/ is a
+// genuinely distinct fetch (not a renamed copy of another version's data),
+// and this sandbox-curated bootstrap subset only exists for 15.1. Pin more
+// versions by adding a go:generate line for each one below (the existing
+// line only fetches 15.1) and running `go generate ./...` against a real
+// network connection, which runs internal/gen against unicode.org, then add
+// the resulting directory name to SupportedVersions below.
+package emojidata
+
+import "embed"
+
+//go:embed versions
+var Versions embed.FS
+
+//go:generate go run ../gen --version=15.1 --out=versions/15.1
+
+// SupportedVersions lists the Unicode emoji versions pinned into Versions.
+// emojisource.FetchVersionedEmojiData only looks in Versions for one of these; any
+// other --version falls back to a live unicode.org fetch over the network.
+var SupportedVersions = []string{"15.1"}
@@ -0,0 +1,125 @@
+// Package emojisource fetches and parses Unicode emoji source files
+// (emoji-test.txt and friends), preferring the pinned offline data in
+// internal/emojidata and falling back to a disk-cached unicode.org
+// download. It is shared by the snippet-pack generator (src/main.go) and
+// cmd/emojiscan, so both tools fetch and parse the same way instead of
+// carrying their own copies of this logic.
+package emojisource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/stgarf/alfredmoji/internal/emojidata"
+)
+
+// FetchEmojiData downloads the emoji data from the provided URL, using a
+// local copy in the current directory instead if one already exists.
+func FetchEmojiData(url string) ([]string, error) {
+	_, filename := path.Split(url)
+
+	if _, err := os.Stat(filename); err == nil {
+		fmt.Printf("Using existing file: %s\n", filename)
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		return lines, scanner.Err()
+	}
+
+	fmt.Printf("Downloading file: %s\n", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	os.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644)
+	return lines, nil
+}
+
+// FetchVersionedEmojiData resolves urlTemplate for version using the
+// Unicode data pinned in internal/emojidata when that version is
+// supported, so the pack can be built fully offline. Any other version
+// falls back to FetchEmojiData's disk-cache-or-download path.
+func FetchVersionedEmojiData(urlTemplate string, version string) ([]string, error) {
+	if !isSupportedVersion(version) {
+		return FetchEmojiData(fmt.Sprintf(urlTemplate, version))
+	}
+
+	_, filename := path.Split(fmt.Sprintf(urlTemplate, version))
+
+	data, err := fs.ReadFile(emojidata.Versions, path.Join("versions", version, filename))
+	if err != nil {
+		return FetchEmojiData(fmt.Sprintf(urlTemplate, version))
+	}
+
+	fmt.Printf("NOTE: using pinned offline data for %s: %s. This is a small, manually curated subset (see internal/emojidata), not the full Unicode file. Run `go generate ./...` with network access to refresh it.\n", version, filename)
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// isSupportedVersion reports whether version has pinned data embedded in
+// emojidata.Versions.
+func isSupportedVersion(version string) bool {
+	for _, supported := range emojidata.SupportedVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+// descriptionLineRegexp extracts the emoji and description out of an
+// emoji-test.txt comment, e.g. "# 😀 E1.0 grinning face".
+var descriptionLineRegexp = regexp.MustCompile(`^\s+(.+) E\d+\.\d+ (.+)`)
+
+// ExtractDescriptionAndEmoji extracts the emoji and description from a
+// line of emoji-test.txt, or two empty strings if line doesn't match the
+// expected "# <emoji> E<version> <description>" shape.
+func ExtractDescriptionAndEmoji(line string) (string, string) {
+	matches := descriptionLineRegexp.FindStringSubmatch(line)
+	if len(matches) != 3 {
+		return "", ""
+	}
+
+	emoji := strings.TrimSpace(matches[1])
+
+	// Remove "junk" characters from description
+	description := strings.TrimSpace(strings.ReplaceAll(matches[2], " ", "-"))
+	description = strings.ReplaceAll(description, ",", "")
+	description = strings.ReplaceAll(description, ":", "")
+	description = strings.ReplaceAll(description, "‚Äô", "")
+	description = strings.ReplaceAll(description, "‚Äò", "")
+	description = strings.ReplaceAll(description, "‚Äú", "")
+	description = strings.ReplaceAll(description, "‚Äù", "")
+	return emoji, description
+}
@@ -0,0 +1,68 @@
+// gen is a go:generate-driven tool that downloads and refreshes the pinned
+// Unicode emoji data embedded by internal/emojidata, analogous to the
+// gen_emoji.sh download pattern, so alfredmoji can work fully offline.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// version is the Unicode emoji version to fetch, e.g. "15.1".
+var version = flag.String("version", "15.1", "Unicode emoji version to fetch")
+
+// outDir overrides where the fetched files are written.
+var outDir = flag.String("out", "", "Directory to write fetched files into (default: internal/emojidata/versions/<version>)")
+
+// sources maps each pinned filename to the unicode.org URL template used to
+// fetch it.
+var sources = map[string]string{
+	"emoji-test.txt":          "https://unicode.org/Public/emoji/%s/emoji-test.txt",
+	"emoji-sequences.txt":     "https://unicode.org/Public/emoji/%s/emoji-sequences.txt",
+	"emoji-zwj-sequences.txt": "https://unicode.org/Public/emoji/%s/emoji-zwj-sequences.txt",
+}
+
+func main() {
+	flag.Parse()
+
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Join("internal", "emojidata", "versions", *version)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Printf("Error creating %s: %v\n", dir, err)
+		os.Exit(1)
+	}
+
+	for filename, urlTemplate := range sources {
+		url := fmt.Sprintf(urlTemplate, *version)
+		fmt.Printf("Downloading %s\n", url)
+
+		if err := downloadFile(url, filepath.Join(dir, filename)); err != nil {
+			fmt.Printf("Error fetching %s: %v\n", url, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Refreshed Unicode emoji %s data in %s\n", *version, dir)
+}
+
+// downloadFile fetches url and writes its body to filePath.
+func downloadFile(url string, filePath string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0644)
+}
@@ -18,7 +18,7 @@ package main
 
 import (
 	"archive/zip"
-	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -27,11 +27,12 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
+	"strconv"
 
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/stgarf/alfredmoji/internal/emojisource"
 )
 
 // EmojiData represents a parsed emoji entry.
@@ -39,15 +40,24 @@ type EmojiData struct {
 	Emoji       string
 	Description string
 	Subgroup    string
+	// Aliases holds additional keyword triggers for this emoji beyond
+	// Description, e.g. shortcode forms. Always contains at least Description.
+	Aliases []string
+	// Category and Subcategory come from the iamcal/emoji-data source and
+	// are empty when sourced from unicode.org.
+	Category    string
+	Subcategory string
 }
 
 // AlfredSnippet represents the structure of an Alfred snippet JSON file.
 type AlfredSnippet struct {
 	AlfredSnippet struct {
-		Snippet string `json:"snippet"`
-		UID     string `json:"uid"`
-		Name    string `json:"name"`
-		Keyword string `json:"keyword"`
+		Snippet             string `json:"snippet"`
+		UID                 string `json:"uid"`
+		Name                string `json:"name"`
+		Keyword             string `json:"keyword"`
+		Autoexpand          bool   `json:"autoexpand,omitempty"`
+		DontAutoInsertSpace bool   `json:"dontautoinsertspace,omitempty"`
 	} `json:"alfredsnippet"`
 }
 
@@ -55,94 +65,485 @@ type AlfredSnippet struct {
 var displayEmojis = flag.Bool("emojis", false, "Display emojis instead of generating Alfred snippet pack")
 
 // unicodeVersion is a flag to specify the Unicode version to use.
-var unicodeVersion = flag.String("version", "15.1", "Unicode version to use (default: 15.1)")
+var unicodeVersion = flag.String("version", "15.1", "Unicode version to use; only 15.1 is pinned for offline use in internal/emojidata, other versions require network access (default: 15.1)")
 
 // unicodeEmojiURL is the URL to download the emoji data from.
 var unicodeEmojiURL = "https://unicode.org/Public/emoji/%s/emoji-test.txt"
 
+// emojiSequencesURL and emojiZWJSequencesURL list skin-tone modifier and
+// ZWJ sequences that emoji-test.txt does not spell out on their own.
+var emojiSequencesURL = "https://unicode.org/Public/emoji/%s/emoji-sequences.txt"
+var emojiZWJSequencesURL = "https://unicode.org/Public/emoji/%s/emoji-zwj-sequences.txt"
+
+// skinTones controls how much of the skin-tone/ZWJ sequence data to expand
+// into additional snippets: "none" emits only the emoji-test.txt pass,
+// "default" also emits ZWJ sequences (families, professions), and "all"
+// additionally emits every skin-tone modifier variant.
+var skinTones = flag.String("skin-tones", "none", "Skin-tone and ZWJ sequence expansion: none|default|all")
+
+// dataSource selects which upstream emoji dataset to parse.
+var dataSource = flag.String("source", "unicode", "Emoji data source to use: unicode|iamcal")
+
+// iamcalEmojiURL is the URL to download the iamcal/emoji-data shortcode dataset from.
+var iamcalEmojiURL = "https://github.com/iamcal/emoji-data/raw/master/emoji.json"
+
+// iamcalEmoji mirrors the fields we use from an iamcal/emoji-data emoji.json entry.
+type iamcalEmoji struct {
+	Name        string   `json:"name"`
+	Unified     string   `json:"unified"`
+	ShortName   string   `json:"short_name"`
+	ShortNames  []string `json:"short_names"`
+	Category    string   `json:"category"`
+	Subcategory string   `json:"subcategory"`
+}
+
+// keywordPrefix and keywordSuffix wrap every generated keyword, e.g. ":grin:".
+var keywordPrefix = flag.String("prefix", ":", "Keyword prefix, applied to every generated snippet keyword")
+var keywordSuffix = flag.String("suffix", ":", "Keyword suffix, applied to every generated snippet keyword")
+
+// autoexpand and dontAutoInsertSpace control Alfred Text Action metadata
+// written into each snippet JSON file.
+var autoexpand = flag.Bool("autoexpand", false, "Mark generated snippets as Alfred Text Action autoexpand triggers")
+var dontAutoInsertSpace = flag.Bool("dontautoinsertspace", false, "Mark generated snippets to not auto-insert a trailing space")
+
+// customDir points alfredmoji at a directory of custom emoji images, or a
+// Mastodon/Pleroma-style pack.json manifest (shortcode -> image URL), to
+// build a portable custom-image snippet pack instead of parsing Unicode or
+// iamcal emoji data.
+var customDir = flag.String("custom-dir", "", "Directory of custom emoji images, or a pack.json manifest, to import as a custom-image snippet pack")
+
+// customTemplate controls the snippet text emitted for each custom emoji.
+var customTemplate = flag.String("custom-template", ":{shortcode}:", `Snippet text template for custom emoji; supports {shortcode}, {file}, and {url}`)
+
+// subgroupPrefixFlag accumulates repeatable "subgroup=prefix" pairs, e.g.
+// "smileys=:s-:", overriding keywordPrefix for snippets in that subgroup.
+type subgroupPrefixFlag map[string]string
+
+func (f subgroupPrefixFlag) String() string {
+	var parts []string
+	for subgroup, prefix := range f {
+		parts = append(parts, fmt.Sprintf("%s=%s", subgroup, prefix))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f subgroupPrefixFlag) Set(value string) error {
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid --subgroup-prefix %q, expected subgroup=prefix", value)
+	}
+	f[kv[0]] = kv[1]
+	return nil
+}
+
+// subgroupPrefixes holds the per-subgroup keyword prefix overrides.
+var subgroupPrefixes = make(subgroupPrefixFlag)
+
+func init() {
+	flag.Var(subgroupPrefixes, "subgroup-prefix", `Per-subgroup keyword prefix override, e.g. "smileys=:s-:" (repeatable)`)
+}
+
+// keywordPrefixFor returns the keyword prefix to use for a given subgroup,
+// honoring any --subgroup-prefix override before falling back to --prefix.
+func keywordPrefixFor(subgroup string) string {
+	if prefix, ok := subgroupPrefixes[subgroup]; ok {
+		return prefix
+	}
+	return *keywordPrefix
+}
+
 // generateUID generates a unique identifier.
 func generateUID() string {
 	return strings.ToUpper(uuid.New().String())
 }
 
-// generateInfoPlist creates the info.plist file for the Alfred snippet pack.
-func generateInfoPlist(filePath string) error {
-	plistContent := `<?xml version="1.0" encoding="UTF-8"?>
+// generateInfoPlist creates the info.plist file for the Alfred snippet pack,
+// using the top-level --prefix/--suffix flags as the pack-wide defaults.
+func generateInfoPlist(filePath string, prefix string, suffix string) error {
+	plistContent := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
 <plist version="1.0">
 <dict>
     <key>snippetkeywordprefix</key>
-    <string>:</string>
+    <string>%s</string>
     <key>snippetkeywordsuffix</key>
-    <string>:</string>
+    <string>%s</string>
 </dict>
-</plist>`
+</plist>`, prefix, suffix)
 
 	return os.WriteFile(filePath, []byte(plistContent), 0644)
 }
 
-// fetchEmojiData downloads the emoji data from the provided URL if it does not exist locally.
-func fetchEmojiData(url string) ([]string, error) {
-	// Get filename from URL
+// fetchRawData downloads the raw bytes at the provided URL, using a local
+// copy in the current directory instead if one already exists.
+func fetchRawData(url string) ([]byte, error) {
 	_, filename := path.Split(url)
 
-	// Hold lines from file
-	var lines []string
-
-	if _, err := os.Stat(filename); err == nil {
-		// If file exists
+	if data, err := os.ReadFile(filename); err == nil {
 		fmt.Printf("Using existing file: %s\n", filename)
-		file, err := os.Open(filename)
+		return data, nil
+	}
+
+	fmt.Printf("Downloading file: %s\n", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	os.WriteFile(filename, data, 0644)
+	return data, nil
+}
+
+// fetchIamcalEmojiData downloads and parses the iamcal/emoji-data emoji.json dataset.
+func fetchIamcalEmojiData(url string) ([]iamcalEmoji, error) {
+	data, err := fetchRawData(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var emojis []iamcalEmoji
+	if err := json.Unmarshal(data, &emojis); err != nil {
+		return nil, err
+	}
+
+	return emojis, nil
+}
+
+// UnifiedToChar converts an iamcal/emoji-data "unified" field (e.g.
+// "1F600" or "1F468-200D-1F469-200D-1F467") into its emoji rune sequence.
+func UnifiedToChar(unified string) string {
+	var emojiRunes []rune
+
+	for _, cp := range strings.Split(unified, "-") {
+		runeValue, err := strconv.ParseInt(cp, 16, 32)
 		if err != nil {
-			return nil, err
+			continue // Skip invalid code point
 		}
-		defer file.Close()
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
+		emojiRunes = append(emojiRunes, rune(runeValue))
+	}
+
+	return string(emojiRunes)
+}
+
+// parseIamcalEmoji converts an iamcal/emoji-data entry into an EmojiData,
+// using its short_name/short_names as keyword aliases instead of a
+// kebab-cased Unicode description.
+func parseIamcalEmoji(entry iamcalEmoji) *EmojiData {
+	if entry.Unified == "" || entry.ShortName == "" {
+		return nil
+	}
+
+	aliases := []string{entry.ShortName}
+	for _, alias := range entry.ShortNames {
+		if alias != entry.ShortName {
+			aliases = append(aliases, alias)
 		}
-	} else {
-		// File does not exist, download
-		fmt.Printf("Downloading file: %s\n", url)
-		file, err := http.Get(url)
+	}
+
+	return &EmojiData{
+		Emoji:       UnifiedToChar(entry.Unified),
+		Description: entry.ShortName,
+		Subgroup:    entry.Subcategory,
+		Aliases:     aliases,
+		Category:    entry.Category,
+		Subcategory: entry.Subcategory,
+	}
+}
+
+// customEmojiEntry is one imported custom-image emoji: a shortcode, the
+// local path to its image, and the remote URL it was sourced from (if any).
+type customEmojiEntry struct {
+	Shortcode string
+	ImagePath string
+	URL       string
+}
+
+// imageMagic maps a recognized image type to its leading magic bytes,
+// mirroring the sniffing freetype's imghdr check performs.
+var imageMagic = map[string][]byte{
+	"png": {0x89, 'P', 'N', 'G'},
+	"gif": {'G', 'I', 'F', '8'},
+}
+
+// detectImageType sniffs a file's header and returns "png", "gif", or
+// "webp" if recognized.
+func detectImageType(filePath string) (string, bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	header := make([]byte, 12)
+	n, _ := io.ReadFull(file, header)
+	if n < 4 {
+		return "", false
+	}
+
+	switch {
+	case bytes.HasPrefix(header, imageMagic["png"]):
+		return "png", true
+	case bytes.HasPrefix(header, imageMagic["gif"]):
+		return "gif", true
+	case n >= 12 && bytes.HasPrefix(header, []byte("RIFF")) && string(header[8:12]) == "WEBP":
+		return "webp", true
+	default:
+		return "", false
+	}
+}
+
+// renderCustomSnippet fills in customTemplate's {shortcode}/{file}/{url} placeholders.
+func renderCustomSnippet(template string, shortcode string, file string, url string) string {
+	out := strings.ReplaceAll(template, "{shortcode}", shortcode)
+	out = strings.ReplaceAll(out, "{file}", file)
+	out = strings.ReplaceAll(out, "{url}", url)
+	return out
+}
+
+// collectCustomEmojiFromDir walks dir and returns one entry per recognized
+// image file, using the filename (without extension) as the shortcode.
+func collectCustomEmojiFromDir(dir string) ([]customEmojiEntry, error) {
+	var entries []customEmojiEntry
+
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if info.IsDir() {
+			return nil
 		}
-		defer file.Body.Close()
-		scanner := bufio.NewScanner(file.Body)
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
+		if _, ok := detectImageType(filePath); !ok {
+			return nil
 		}
-		// Write file to disk
-		os.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644)
+
+		name := filepath.Base(filePath)
+		shortcode := strings.TrimSuffix(name, filepath.Ext(name))
+		entries = append(entries, customEmojiEntry{Shortcode: shortcode, ImagePath: filePath})
+		return nil
+	})
+
+	return entries, err
+}
+
+// sanitizeShortcode rejects shortcodes/aliases that could escape the
+// build/ directory when used in a filepath.Join. Shortcodes can originate
+// from untrusted third-party sources, whether a pack.json manifest or a
+// downloaded emoji.json's short_name, and a value like "../../etc/passwd"
+// would otherwise let us write or name files outside build/.
+func sanitizeShortcode(shortcode string) error {
+	if shortcode == "" || shortcode == ".." || shortcode != filepath.Base(shortcode) {
+		return fmt.Errorf("invalid shortcode %q", shortcode)
 	}
+	return nil
+}
+
+// downloadCustomEmojiImage downloads a remote custom emoji image into
+// build/, naming it after its shortcode and the type sniffed from its magic bytes.
+func downloadCustomEmojiImage(shortcode string, url string) (string, error) {
+	if err := sanitizeShortcode(shortcode); err != nil {
+		return "", err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
 
-	return lines, nil
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	tmpPath := filepath.Join("build", shortcode)
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return "", err
+	}
+
+	imageType, ok := detectImageType(tmpPath)
+	if !ok {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("unrecognized image type for %s", shortcode)
+	}
+
+	imagePath := tmpPath + "." + imageType
+	if err := os.Rename(tmpPath, imagePath); err != nil {
+		return "", err
+	}
+
+	return imagePath, nil
 }
 
-// extractDescriptionAndEmoji extracts the emoji and description from a line of emoji data.
-func extractDescriptionAndEmoji(input string) (string, string) {
-	// regex for: '# üòÄ E1.0 grinning face'
-	re := regexp.MustCompile(`^\s+(.+) E\d+\.\d+ (.+)`)
-	matches := re.FindStringSubmatch(input)
+// collectCustomEmojiFromManifest reads a Mastodon/Pleroma-style pack.json
+// manifest (shortcode -> image URL) and downloads each image into build/.
+func collectCustomEmojiFromManifest(manifestPath string) ([]customEmojiEntry, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
 
-	// if we got the right number of matches, return the emoji and description
-	if len(matches) == 3 {
-		emoji := strings.TrimSpace(matches[1])
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
 
-		// Remove "junk" characters from description
-		description := strings.TrimSpace(strings.ReplaceAll(matches[2], " ", "-"))
-		description = strings.ReplaceAll(description, ",", "")
-		description = strings.ReplaceAll(description, ":", "")
-		description = strings.ReplaceAll(description, "‚Äô", "")
-		description = strings.ReplaceAll(description, "‚Äò", "")
-		description = strings.ReplaceAll(description, "‚Äú", "")
-		description = strings.ReplaceAll(description, "‚Äù", "")
-		return emoji, description
+	var entries []customEmojiEntry
+	for shortcode, url := range manifest {
+		imagePath, err := downloadCustomEmojiImage(shortcode, url)
+		if err != nil {
+			fmt.Printf("Error downloading %s: %v\n", shortcode, err)
+			continue
+		}
+		entries = append(entries, customEmojiEntry{Shortcode: shortcode, ImagePath: imagePath, URL: url})
 	}
 
-	// otherwise, return empty strings
-	return "", ""
+	return entries, nil
+}
+
+// generateCustomSnippetJSON creates a JSON file for a custom-image Alfred
+// snippet, using customTemplate to render the snippet's text.
+func generateCustomSnippetJSON(entry customEmojiEntry, uid string, filePath string) error {
+	snippet := AlfredSnippet{}
+	snippet.AlfredSnippet.Snippet = renderCustomSnippet(*customTemplate, entry.Shortcode, filepath.Base(entry.ImagePath), entry.URL)
+	snippet.AlfredSnippet.UID = uid
+	snippet.AlfredSnippet.Name = entry.Shortcode
+	snippet.AlfredSnippet.Keyword = *keywordPrefix + entry.Shortcode + *keywordSuffix
+	snippet.AlfredSnippet.Autoexpand = *autoexpand
+	snippet.AlfredSnippet.DontAutoInsertSpace = *dontAutoInsertSpace
+
+	jsonData, err := json.Marshal(snippet)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, jsonData, 0644)
+}
+
+// runCustomPackMode builds a custom-image .alfredsnippets pack from a
+// directory of images, or a pack.json shortcode->URL manifest, at path.
+func runCustomPackMode(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var entries []customEmojiEntry
+	if info.IsDir() {
+		entries, err = collectCustomEmojiFromDir(path)
+	} else {
+		entries, err = collectCustomEmojiFromManifest(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var filesToZip []string
+	for _, entry := range entries {
+		if err := sanitizeShortcode(entry.Shortcode); err != nil {
+			fmt.Printf("Skipping %v: %v\n", entry.Shortcode, err)
+			continue
+		}
+
+		uid := generateUID()
+		fileName := filepath.Join("build", fmt.Sprintf("%s [%s].json", entry.Shortcode, uid))
+		if err := generateCustomSnippetJSON(entry, uid, fileName); err != nil {
+			fmt.Printf("Error generating JSON for %v: %v\n", entry.Shortcode, err)
+			continue
+		}
+		filesToZip = append(filesToZip, fileName, entry.ImagePath)
+	}
+
+	plistFileName := filepath.Join("build", "info.plist")
+	if err := generateInfoPlist(plistFileName, *keywordPrefix, *keywordSuffix); err != nil {
+		return err
+	}
+	filesToZip = append(filesToZip, plistFileName)
+
+	zipFileName := filepath.Join("dist", "alfredmoji-custom.alfredsnippets")
+	if err := zipFiles(zipFileName, filesToZip); err != nil {
+		return err
+	}
+
+	fmt.Println("alfredmoji.alfredsnippets file created successfully.")
+	os.RemoveAll("build")
+	return nil
+}
+
+// codePointsToEmoji converts a whitespace-separated list of hex code points,
+// as used in emoji-sequences.txt and emoji-zwj-sequences.txt, into its
+// emoji rune sequence.
+func codePointsToEmoji(codePoints string) string {
+	return UnifiedToChar(strings.Join(strings.Fields(codePoints), "-"))
+}
+
+// slugifyDescription turns a Unicode emoji description into a snippet
+// keyword, following the same cleanup emojisource.ExtractDescriptionAndEmoji applies.
+func slugifyDescription(description string) string {
+	slug := strings.TrimSpace(strings.ReplaceAll(description, " ", "-"))
+	slug = strings.ReplaceAll(slug, ",", "")
+	slug = strings.ReplaceAll(slug, ":", "")
+	return slug
+}
+
+// parseSequenceLine parses one line of emoji-sequences.txt or
+// emoji-zwj-sequences.txt, returning nil unless its sequence type matches
+// wantType ("Emoji_Modifier_Sequence" or "RGI_Emoji_ZWJ_Sequence").
+func parseSequenceLine(line string, wantType string) *EmojiData {
+	if strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	parts := strings.SplitN(line, ";", 3)
+	if len(parts) < 3 {
+		return nil
+	}
+
+	if strings.TrimSpace(parts[1]) != wantType {
+		return nil
+	}
+
+	codePoints := strings.TrimSpace(parts[0])
+	description := strings.TrimSpace(strings.Split(parts[2], "#")[0])
+
+	alias := slugifyDescription(description)
+	subgroup := "zwj-sequences"
+	if wantType == "Emoji_Modifier_Sequence" {
+		// Descriptions read "runner: medium-light skin tone"; drop the
+		// "skin tone" suffix so the alias reads "runner-medium-light".
+		alias = strings.TrimSuffix(alias, "-skin-tone")
+		subgroup = "skin-tones"
+	}
+
+	return &EmojiData{
+		Emoji:       codePointsToEmoji(codePoints),
+		Description: alias,
+		Subgroup:    subgroup,
+		Aliases:     []string{alias},
+	}
+}
+
+// fetchSequenceEmoji downloads an emoji-sequences.txt-shaped file for the
+// given Unicode version and parses out every entry matching wantType.
+func fetchSequenceEmoji(urlTemplate string, version string, wantType string) ([]*EmojiData, error) {
+	lines, err := emojisource.FetchVersionedEmojiData(urlTemplate, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var emojis []*EmojiData
+	for _, line := range lines {
+		if emoji := parseSequenceLine(line, wantType); emoji != nil {
+			emojis = append(emojis, emoji)
+		}
+	}
+
+	return emojis, nil
 }
 
 // parseEmojiLine parses a line of emoji data and updates the current subgroup if needed.
@@ -165,7 +566,7 @@ func parseEmojiLine(line string, currentSubgroup *string) []*EmojiData {
 	parts := strings.SplitN(line, ";", 2)
 	// Get the interesting bits
 	interestingBits := strings.Split(parts[1], "#")[1]
-	emoji, description := extractDescriptionAndEmoji(interestingBits)
+	emoji, description := emojisource.ExtractDescriptionAndEmoji(interestingBits)
 
 	var emojis []*EmojiData
 	// Add emoji to list
@@ -173,20 +574,25 @@ func parseEmojiLine(line string, currentSubgroup *string) []*EmojiData {
 		Emoji:       emoji,
 		Description: description,
 		Subgroup:    *currentSubgroup,
+		Aliases:     []string{description},
 	})
 
 	return emojis
 }
 
-// generateAlfredSnippetJSON creates a JSON file for an Alfred snippet.
-func generateAlfredSnippetJSON(emoji EmojiData, emojiChar string, uid string, filePath string) error {
+// generateAlfredSnippetJSON creates a JSON file for an Alfred snippet using
+// keyword as the snippet's trigger, wrapped in the subgroup's keyword prefix
+// and the global keyword suffix.
+func generateAlfredSnippetJSON(emoji EmojiData, emojiChar string, keyword string, uid string, filePath string) error {
 	// Create snippet
 	snippet := AlfredSnippet{}
 	// Set values
 	snippet.AlfredSnippet.Snippet = emojiChar
 	snippet.AlfredSnippet.UID = uid
 	snippet.AlfredSnippet.Name = fmt.Sprintf("(%s) %s", emoji.Subgroup, emoji.Description)
-	snippet.AlfredSnippet.Keyword = emoji.Description
+	snippet.AlfredSnippet.Keyword = keywordPrefixFor(emoji.Subgroup) + keyword + *keywordSuffix
+	snippet.AlfredSnippet.Autoexpand = *autoexpand
+	snippet.AlfredSnippet.DontAutoInsertSpace = *dontAutoInsertSpace
 
 	// Marshal snippet to JSON
 	jsonData, err := json.Marshal(snippet)
@@ -248,6 +654,40 @@ func addFileToZip(zipWriter *zip.Writer, filename string) error {
 	return err
 }
 
+// processEmoji either prints the emoji (when --emojis is set) or generates
+// one Alfred snippet JSON file per alias, appending each to filesToZip.
+func processEmoji(emoji *EmojiData, filesToZip *[]string) {
+	if *displayEmojis {
+		fmt.Printf("%s: %s\n", emoji.Emoji, emoji.Description)
+		return
+	}
+
+	// Generate one JSON file per alias, so an emoji with multiple keyword
+	// triggers ships multiple snippets
+	for _, alias := range emoji.Aliases {
+		// Aliases can come straight from a downloaded emoji.json's
+		// short_name/short_names (see parseIamcalEmoji), so they're
+		// untrusted the same way pack.json shortcodes are.
+		if err := sanitizeShortcode(alias); err != nil {
+			fmt.Printf("Skipping alias for %v: %v\n", emoji.Description, err)
+			continue
+		}
+
+		// Generate a unique identifier
+		uid := generateUID()
+
+		// Generate JSON filename
+		fileName := filepath.Join("build", fmt.Sprintf("%s [%s].json", alias, uid))
+		// Generate JSON file and add to list of files to zip
+		err := generateAlfredSnippetJSON(*emoji, emoji.Emoji, alias, uid, fileName)
+		if err != nil {
+			fmt.Printf("Error generating JSON for %v: %v\n", alias, err)
+		} else {
+			*filesToZip = append(*filesToZip, fileName)
+		}
+	}
+}
+
 func main() {
 	// Create build and dist directories
 	os.Mkdir("build", 0755)
@@ -256,58 +696,81 @@ func main() {
 	// Parse cmd line flags
 	flag.Parse()
 
-	// Get emojis from unicode.org
-	url := fmt.Sprintf(unicodeEmojiURL, *unicodeVersion)
-
-	// Get lines from file
-	lines, err := fetchEmojiData(url)
-	if err != nil {
-		fmt.Printf("Error fetching emoji data: %v\n", err)
+	if *customDir != "" {
+		if err := runCustomPackMode(*customDir); err != nil {
+			fmt.Printf("Error generating custom-image snippet pack: %v\n", err)
+		}
 		return
 	}
 
 	// To keep track of all files to be zipped
 	var filesToZip []string
 
-	// Keep track of current subgroup
-	var currentSubgroup *string
+	if *dataSource == "iamcal" {
+		// Get emojis from iamcal/emoji-data
+		entries, err := fetchIamcalEmojiData(iamcalEmojiURL)
+		if err != nil {
+			fmt.Printf("Error fetching emoji data: %v\n", err)
+			return
+		}
 
-	// Loop through each line
-	for _, line := range lines {
-		// If the line starts with "# subgroup:", update the current subgroup, else parse the line
-		if strings.HasPrefix(line, "# subgroup:") {
-			// Clean up subgroup name
-			subgroup := strings.TrimSpace(strings.TrimPrefix(line, "# subgroup:"))
-			subgroup = strings.ReplaceAll(subgroup, "&", "and")
-			subgroup = strings.ReplaceAll(subgroup, " ", "-")
-			// Set current subgroup
-			currentSubgroup = &subgroup
-			// Skip to next line
-			continue
-		} else if currentSubgroup != nil {
-			// Parse the line and get the emoji data
-			emojiData := parseEmojiLine(line, currentSubgroup)
-
-			// If we got emoji data, generate Alfred snippet JSON
-			for _, emoji := range emojiData {
-				// If flag is set to display emojis, display them instead of generating snippet pack
-				if !*displayEmojis {
-					// Generate a unique identifier
-					uid := generateUID()
-
-					// Generate JSON filename
-					fileName := filepath.Join("build", fmt.Sprintf("%s [%s].json", emoji.Description, uid))
-					// Generate JSON file and add to list of files to zip
-					err := generateAlfredSnippetJSON(*emoji, emoji.Emoji, uid, fileName)
-					if err != nil {
-						fmt.Printf("Error generating JSON for %v: %v\n", emoji.Description, err)
-					} else {
-						filesToZip = append(filesToZip, fileName)
-					}
-				} else {
-					// Display emoji
-					emojiChar := emoji.Emoji
-					fmt.Printf("%s: %s\n", emojiChar, emoji.Description)
+		for _, entry := range entries {
+			if emoji := parseIamcalEmoji(entry); emoji != nil {
+				processEmoji(emoji, &filesToZip)
+			}
+		}
+	} else {
+		// Get emojis from the pinned offline dataset, falling back to unicode.org
+		lines, err := emojisource.FetchVersionedEmojiData(unicodeEmojiURL, *unicodeVersion)
+		if err != nil {
+			fmt.Printf("Error fetching emoji data: %v\n", err)
+			return
+		}
+
+		// Keep track of current subgroup
+		var currentSubgroup *string
+
+		// Loop through each line
+		for _, line := range lines {
+			// If the line starts with "# subgroup:", update the current subgroup, else parse the line
+			if strings.HasPrefix(line, "# subgroup:") {
+				// Clean up subgroup name
+				subgroup := strings.TrimSpace(strings.TrimPrefix(line, "# subgroup:"))
+				subgroup = strings.ReplaceAll(subgroup, "&", "and")
+				subgroup = strings.ReplaceAll(subgroup, " ", "-")
+				// Set current subgroup
+				currentSubgroup = &subgroup
+				// Skip to next line
+				continue
+			} else if currentSubgroup != nil {
+				// Parse the line and get the emoji data
+				emojiData := parseEmojiLine(line, currentSubgroup)
+
+				// If we got emoji data, generate Alfred snippet JSON
+				for _, emoji := range emojiData {
+					processEmoji(emoji, &filesToZip)
+				}
+			}
+		}
+
+		if *skinTones == "default" || *skinTones == "all" {
+			zwjEmoji, err := fetchSequenceEmoji(emojiZWJSequencesURL, *unicodeVersion, "RGI_Emoji_ZWJ_Sequence")
+			if err != nil {
+				fmt.Printf("Error fetching ZWJ sequence data: %v\n", err)
+			} else {
+				for _, emoji := range zwjEmoji {
+					processEmoji(emoji, &filesToZip)
+				}
+			}
+		}
+
+		if *skinTones == "all" {
+			modifierEmoji, err := fetchSequenceEmoji(emojiSequencesURL, *unicodeVersion, "Emoji_Modifier_Sequence")
+			if err != nil {
+				fmt.Printf("Error fetching skin-tone sequence data: %v\n", err)
+			} else {
+				for _, emoji := range modifierEmoji {
+					processEmoji(emoji, &filesToZip)
 				}
 			}
 		}
@@ -316,7 +779,7 @@ func main() {
 	if !*displayEmojis {
 		// Generate info.plist file and add to list of files to zip
 		plistFileName := filepath.Join("build", "info.plist")
-		err = generateInfoPlist(plistFileName)
+		err := generateInfoPlist(plistFileName, *keywordPrefix, *keywordSuffix)
 		if err != nil {
 			fmt.Printf("Error generating info.plist: %v\n", err)
 		} else {
@@ -328,7 +791,11 @@ func main() {
 		filesToZip = append(filesToZip, iconFileName)
 
 		// Generate zip file name
-		packName := fmt.Sprintf("alfredmoji-%s.alfredsnippets", *unicodeVersion)
+		packVersion := *unicodeVersion
+		if *dataSource == "iamcal" {
+			packVersion = "iamcal"
+		}
+		packName := fmt.Sprintf("alfredmoji-%s.alfredsnippets", packVersion)
 		zipFileName := filepath.Join("dist", packName)
 		// Zip files
 		err = zipFiles(zipFileName, filesToZip)
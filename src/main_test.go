@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestSanitizeShortcode(t *testing.T) {
+	tests := []struct {
+		shortcode string
+		wantErr   bool
+	}{
+		{"grinning", false},
+		{"thumbs-up-medium", false},
+		{"", true},
+		{"..", true},
+		{"../../../../etc/passwd", true},
+		{"../evil", true},
+		{"foo/../../bar", true},
+		{"nested/shortcode", true},
+		{"/absolute", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shortcode, func(t *testing.T) {
+			err := sanitizeShortcode(tt.shortcode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sanitizeShortcode(%q) error = %v, wantErr %v", tt.shortcode, err, tt.wantErr)
+			}
+		})
+	}
+}